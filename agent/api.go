@@ -0,0 +1,198 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// rawItem is one list item whose full schema lives outside this package; it
+// is decoded lazily by the caller via Value().
+type rawItem = json.RawMessage
+
+// httpClient abstracts the transport used to call Agent API actions: it
+// sends action with payload and returns the raw JSON response body, rather
+// than decoding into a caller-supplied struct, so this package controls how
+// the response is decoded (see listActions and decodePage below) without
+// forcing a response shape on the transport.
+type httpClient interface {
+	Call(ctx context.Context, action string, payload interface{}) (json.RawMessage, error)
+}
+
+// listActions names the list_* action and the response field holding the
+// page of items for one resource. The defaults below are this package's
+// best-effort reading of the Agent API's list endpoints and have not been
+// confirmed against a live server. Rather than hiding a wrong guess behind
+// a silently-empty result, ListArchives/ListChats/ListThreads/ListCustomers
+// return an explicit error naming the fields the response actually
+// contained when the configured field is missing; use the API.Set*Actions
+// methods to correct the names for your server.
+type listActions struct {
+	action string
+	field  string
+}
+
+var (
+	defaultArchivesActions  = listActions{action: "list_archives", field: "archives"}
+	defaultChatsActions     = listActions{action: "list_chats", field: "chats"}
+	defaultThreadsActions   = listActions{action: "list_threads", field: "threads"}
+	defaultCustomersActions = listActions{action: "list_customers", field: "customers"}
+)
+
+// API is the Agent API client. Build one with NewAPI.
+type API struct {
+	client httpClient
+
+	archives  listActions
+	chats     listActions
+	threads   listActions
+	customers listActions
+}
+
+// NewAPI creates an Agent API client that issues requests through client,
+// using this package's default list_* action and response-field names. Use
+// the Set*Actions methods if your server uses different ones.
+func NewAPI(client httpClient) *API {
+	return &API{
+		client:    client,
+		archives:  defaultArchivesActions,
+		chats:     defaultChatsActions,
+		threads:   defaultThreadsActions,
+		customers: defaultCustomersActions,
+	}
+}
+
+// SetArchivesActions overrides the list_archives action name and the
+// response field holding the page of archives.
+func (a *API) SetArchivesActions(action, field string) {
+	a.archives = listActions{action: action, field: field}
+}
+
+// SetChatsActions overrides the list_chats action name and the response
+// field holding the page of chats.
+func (a *API) SetChatsActions(action, field string) {
+	a.chats = listActions{action: action, field: field}
+}
+
+// SetThreadsActions overrides the list_threads action name and the response
+// field holding the page of threads.
+func (a *API) SetThreadsActions(action, field string) {
+	a.threads = listActions{action: action, field: field}
+}
+
+// SetCustomersActions overrides the list_customers action name and the
+// response field holding the page of customers.
+func (a *API) SetCustomersActions(action, field string) {
+	a.customers = listActions{action: action, field: field}
+}
+
+// listRequest is the common request shape for every list_* action: the
+// filters for that resource plus paging, ordering and field-projection
+// controls. sort_by/sort_order/fields are lifted out of the filter
+// builder's queryModifiers (set via SortBy/Select) rather than nested
+// inside Filters, matching the wire format.
+type listRequest struct {
+	Filters   interface{} `json:"filters,omitempty"`
+	PageID    string      `json:"page_id,omitempty"`
+	Limit     int         `json:"limit,omitempty"`
+	SortBy    string      `json:"sort_by,omitempty"`
+	SortOrder string      `json:"sort_order,omitempty"`
+	Fields    []string    `json:"fields,omitempty"`
+}
+
+// newListRequest builds a listRequest for filters, lifting any sort/field
+// options attached via SortBy/Select to the top level. opts.SortOrder is
+// used as a fallback when the filter itself didn't call SortBy.
+func newListRequest(filters interface{}, pageID string, opts IterOpts, mods *queryModifiers) listRequest {
+	req := listRequest{Filters: filters, PageID: pageID, Limit: opts.PageSize, SortOrder: opts.SortOrder}
+	if mods != nil {
+		if mods.SortBy != "" {
+			req.SortBy = mods.SortBy
+			req.SortOrder = mods.SortOrder
+		}
+		req.Fields = mods.Fields
+	}
+	return req
+}
+
+// decodePage extracts the page of items and the next page ID from a list_*
+// response body. It returns an error naming the fields the response actually
+// had if the configured field isn't one of them, instead of decoding to a
+// zero-length page that looks indistinguishable from a legitimately empty
+// result set.
+func decodePage(action, field string, body json.RawMessage) ([]rawItem, string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, "", fmt.Errorf("agent: decode %s response: %w", action, err)
+	}
+	fieldData, ok := raw[field]
+	if !ok {
+		keys := make([]string, 0, len(raw))
+		for k := range raw {
+			keys = append(keys, k)
+		}
+		return nil, "", fmt.Errorf("agent: %s response has no %q field (response fields: %v); configure the right one with API.Set*Actions if your server differs", action, field, keys)
+	}
+	var items []rawItem
+	if len(fieldData) > 0 && string(fieldData) != "null" {
+		if err := json.Unmarshal(fieldData, &items); err != nil {
+			return nil, "", fmt.Errorf("agent: %s response field %q: %w", action, field, err)
+		}
+	}
+	var page struct {
+		NextPageID string `json:"next_page_id"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, "", fmt.Errorf("agent: decode %s response: %w", action, err)
+	}
+	return items, page.NextPageID, nil
+}
+
+func (a *API) listPage(ctx context.Context, la listActions, req listRequest) ([]rawItem, string, error) {
+	body, err := a.client.Call(ctx, la.action, req)
+	if err != nil {
+		return nil, "", err
+	}
+	return decodePage(la.action, la.field, body)
+}
+
+// ListArchives returns one page of archives matching f, starting at pageID
+// ("" for the first page). It is primarily used internally by IterArchives;
+// callers that don't need auto-pagination can use it directly.
+func (a *API) ListArchives(ctx context.Context, f *archivesFilters, pageID string, opts IterOpts) ([]rawItem, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+	req := newListRequest(f, pageID, opts, f.queryModifiers())
+	return a.listPage(ctx, a.archives, req)
+}
+
+// ListChats returns one page of chats matching f, starting at pageID ("" for
+// the first page).
+func (a *API) ListChats(ctx context.Context, f *chatsFilters, pageID string, opts IterOpts) ([]rawItem, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+	req := newListRequest(f, pageID, opts, f.queryModifiers())
+	return a.listPage(ctx, a.chats, req)
+}
+
+// ListThreads returns one page of threads matching f, starting at pageID
+// ("" for the first page).
+func (a *API) ListThreads(ctx context.Context, f *threadsFilters, pageID string, opts IterOpts) ([]rawItem, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+	req := newListRequest(f, pageID, opts, f.queryModifiers())
+	return a.listPage(ctx, a.threads, req)
+}
+
+// ListCustomers returns one page of customers matching f, starting at
+// pageID ("" for the first page).
+func (a *API) ListCustomers(ctx context.Context, f *customersFilters, pageID string, opts IterOpts) ([]rawItem, string, error) {
+	if err := f.Validate(); err != nil {
+		return nil, "", err
+	}
+	req := newListRequest(f, pageID, opts, f.queryModifiers())
+	return a.listPage(ctx, a.customers, req)
+}
@@ -0,0 +1,553 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseFilterExpr parses a compact, human-writable filter expression into the
+// filter builder for target, which must be one of "archives", "chats" or
+// "customers". The returned value is a *archivesFilters, *chatsFilters or
+// *customersFilters respectively.
+//
+// The grammar is a comma-separated list of clauses:
+//
+//	field OP value
+//
+// where OP is one of =, !=, in, !in, >, >=, <, <=, between, and value is a
+// quoted string, a bare identifier, an integer, an ISO-8601 datetime, or a
+// bracketed list of those, e.g. [1,2,3]. A `between` clause takes two values
+// separated by "..", e.g. `created_at between 2024-01-01T00:00:00Z..2024-02-01T00:00:00Z`.
+// Dotted field paths of the form properties.<namespace>.<name> address
+// PropertiesFilters entries, e.g. `properties.routing.group_id in [1,2]`.
+func ParseFilterExpr(target string, expr string) (interface{}, error) {
+	clauses, err := splitClauses(expr)
+	if err != nil {
+		return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+	}
+
+	switch target {
+	case "archives":
+		af := NewArchivesFilters()
+		var props PropertiesFilters
+		for _, c := range clauses {
+			if strings.HasPrefix(c.field, "properties.") {
+				if err := applyPropertiesClause(&props, c.field, c.op, c.value); err != nil {
+					return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+				}
+				continue
+			}
+			if err := applyArchivesClause(af, c); err != nil {
+				return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+			}
+		}
+		if props != nil {
+			af.ByProperties(props)
+		}
+		return af, nil
+	case "chats":
+		cf := NewChatsFilters()
+		var props PropertiesFilters
+		for _, c := range clauses {
+			if strings.HasPrefix(c.field, "properties.") {
+				if err := applyPropertiesClause(&props, c.field, c.op, c.value); err != nil {
+					return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+				}
+				continue
+			}
+			if err := applyChatsClause(cf, c); err != nil {
+				return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+			}
+		}
+		if props != nil {
+			cf.ByProperties(props)
+		}
+		return cf, nil
+	case "customers":
+		cf := NewCustomersFilters()
+		for _, c := range clauses {
+			if err := applyCustomersClause(cf, c); err != nil {
+				return nil, fmt.Errorf("agent: parse filter expr: %w", err)
+			}
+		}
+		return cf, nil
+	default:
+		return nil, fmt.Errorf("agent: parse filter expr: unknown target %q", target)
+	}
+}
+
+// filterClause is a single parsed "field OP value" clause.
+type filterClause struct {
+	field string
+	op    string
+	value string
+}
+
+var clauseOps = []string{">=", "<=", "!=", "!in", "between", "=", ">", "<", "in"}
+
+// splitClauses splits a comma-separated expression into clauses, respecting
+// quotes and brackets so that commas inside string literals or lists don't
+// break clauses apart.
+func splitClauses(expr string) ([]filterClause, error) {
+	var clauses []filterClause
+	for _, raw := range splitTopLevel(expr, ',') {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		c, err := parseClause(raw)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, c)
+	}
+	return clauses, nil
+}
+
+// splitTopLevel splits s on sep, ignoring occurrences inside quotes or
+// brackets.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	var depth int
+	var inQuote bool
+	var quoteChar rune
+	start := 0
+	for i, r := range s {
+		switch {
+		case inQuote:
+			if r == quoteChar {
+				inQuote = false
+			}
+		case r == '"' || r == '\'':
+			inQuote = true
+			quoteChar = r
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+func parseClause(raw string) (filterClause, error) {
+	for _, op := range clauseOps {
+		idx := findOp(raw, op)
+		if idx < 0 {
+			continue
+		}
+		field := strings.TrimSpace(raw[:idx])
+		value := strings.TrimSpace(raw[idx+len(op):])
+		if field == "" || value == "" {
+			continue
+		}
+		return filterClause{field: field, op: op, value: value}, nil
+	}
+	return filterClause{}, fmt.Errorf("unrecognized clause %q", raw)
+}
+
+// findOp finds the first occurrence of op surrounded by word boundaries
+// relevant to its kind (symbolic operators just need to appear; keyword
+// operators like "in"/"between" must be standalone words), ignoring any
+// occurrence inside a quoted value so that a clause like `name = ">=weird"`
+// isn't split on the ">=" inside the quotes.
+func findOp(s, op string) int {
+	isWord := op[0] >= 'a' && op[0] <= 'z' || (op[0] == '!' && len(op) > 1 && op[1] >= 'a')
+	var inQuote bool
+	var quoteChar byte
+	for i := 0; i < len(s); i++ {
+		if inQuote {
+			if s[i] == quoteChar {
+				inQuote = false
+			}
+			continue
+		}
+		if s[i] == '"' || s[i] == '\'' {
+			inQuote = true
+			quoteChar = s[i]
+			continue
+		}
+		if i+len(op) > len(s) || s[i:i+len(op)] != op {
+			continue
+		}
+		if !isWord {
+			// Avoid matching "=" inside "!=", ">=", "<=".
+			if op == "=" && i > 0 && (s[i-1] == '!' || s[i-1] == '>' || s[i-1] == '<') {
+				continue
+			}
+			return i
+		}
+		before := byte(' ')
+		if i > 0 {
+			before = s[i-1]
+		}
+		after := byte(' ')
+		if i+len(op) < len(s) {
+			after = s[i+len(op)]
+		}
+		if !isIdentByte(before) && !isIdentByte(after) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '.' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// parseValueList parses a value that is either a single literal or a
+// bracketed, comma-separated list of literals, returning the unquoted
+// literal strings.
+func parseValueList(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		inner := value[1 : len(value)-1]
+		var out []string
+		for _, v := range splitTopLevel(inner, ',') {
+			out = append(out, unquote(strings.TrimSpace(v)))
+		}
+		return out
+	}
+	return []string{unquote(value)}
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 {
+		if (s[0] == '"' && s[len(s)-1] == '"') || (s[0] == '\'' && s[len(s)-1] == '\'') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i, v := range values {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			out[i] = n
+			continue
+		}
+		out[i] = v
+	}
+	return out
+}
+
+func parseBetween(value string) (string, string, error) {
+	parts := strings.SplitN(value, "..", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("between clause requires two values separated by \"..\"")
+	}
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
+}
+
+// applyRangeOp rejects a 0 operand for >, >=, < and <=: RangeFilter's bound
+// fields are plain ints with omitempty, and Validate treats 0 as "unset", so
+// a literal 0 bound would silently vanish on the wire instead of filtering
+// anything.
+func applyRangeOp(field, op, value string, set func(update func(rf *RangeFilter))) error {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if op != "between" && err != nil {
+		return fmt.Errorf("field %q: value %q is not an integer", field, value)
+	}
+	if (op == ">" || op == ">=" || op == "<" || op == "<=") && n == 0 {
+		return fmt.Errorf("field %q: operator %q does not support a 0 operand, since RangeFilter cannot distinguish a 0 bound from an unset one", field, op)
+	}
+	switch op {
+	case ">":
+		set(func(rf *RangeFilter) { rf.GT = n })
+	case ">=":
+		set(func(rf *RangeFilter) { rf.GTE = n })
+	case "<":
+		set(func(rf *RangeFilter) { rf.LT = n })
+	case "<=":
+		set(func(rf *RangeFilter) { rf.LTE = n })
+	case "=":
+		set(func(rf *RangeFilter) { rf.EQ = n })
+	case "between":
+		lo, hi, err := parseBetween(value)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field, err)
+		}
+		loN, err := strconv.Atoi(lo)
+		if err != nil {
+			return fmt.Errorf("field %q: value %q is not an integer", field, lo)
+		}
+		hiN, err := strconv.Atoi(hi)
+		if err != nil {
+			return fmt.Errorf("field %q: value %q is not an integer", field, hi)
+		}
+		if loN == hiN {
+			// A degenerate "between x..x" is a single point, not a range;
+			// collapse it to EQ, the more direct representation.
+			set(func(rf *RangeFilter) { rf.EQ = loN })
+		} else {
+			set(func(rf *RangeFilter) { rf.GTE = loN; rf.LTE = hiN })
+		}
+	default:
+		return fmt.Errorf("field %q: operator %q is not valid for a numeric range", field, op)
+	}
+	return nil
+}
+
+func applyDateRangeOp(field, op, value string, set func(update func(drf *DateRangeFilter))) error {
+	if op != "between" {
+		value = unquote(value)
+	}
+	switch op {
+	case ">":
+		set(func(drf *DateRangeFilter) { drf.GT = value })
+	case ">=":
+		set(func(drf *DateRangeFilter) { drf.GTE = value })
+	case "<":
+		set(func(drf *DateRangeFilter) { drf.LT = value })
+	case "<=":
+		set(func(drf *DateRangeFilter) { drf.LTE = value })
+	case "=":
+		set(func(drf *DateRangeFilter) { drf.EQ = value })
+	case "between":
+		lo, hi, err := parseBetween(value)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", field, err)
+		}
+		lo, hi = unquote(lo), unquote(hi)
+		if lo == hi {
+			// A degenerate "between t..t" is a single instant, not a range;
+			// collapse it to EQ, the more direct representation.
+			set(func(drf *DateRangeFilter) { drf.EQ = lo })
+		} else {
+			set(func(drf *DateRangeFilter) { drf.GTE = lo; drf.LTE = hi })
+		}
+	default:
+		return fmt.Errorf("field %q: operator %q is not valid for a date range", field, op)
+	}
+	return nil
+}
+
+// applyPropertiesClause parses a `properties.<namespace>.<name> OP value`
+// clause into pf.
+func applyPropertiesClause(pf *PropertiesFilters, field, op, value string) error {
+	parts := strings.SplitN(field, ".", 3)
+	if len(parts) != 3 || parts[0] != "properties" {
+		return fmt.Errorf("field %q: expected properties.<namespace>.<name>", field)
+	}
+	namespace, name := parts[1], parts[2]
+
+	var includes bool
+	switch op {
+	case "in", "=":
+		includes = true
+	case "!in", "!=":
+		includes = false
+	default:
+		return fmt.Errorf("field %q: operator %q is not valid for a property filter", field, op)
+	}
+
+	vals := toInterfaceSlice(parseValueList(value))
+	if *pf == nil {
+		*pf = PropertiesFilters{}
+	}
+	if (*pf)[namespace] == nil {
+		(*pf)[namespace] = map[string]*propertyFilterType{}
+	}
+	(*pf)[namespace][name] = NewPropertyFilterType(includes, vals, false)
+	return nil
+}
+
+func applyArchivesClause(af *archivesFilters, c filterClause) error {
+	switch c.field {
+	case "groups":
+		ids, err := parseUintList(parseValueList(c.value))
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		af.GroupIDs = ids
+	case "query":
+		af.Query = unquote(c.value)
+	case "from":
+		af.From = unquote(c.value)
+	case "to":
+		af.To = unquote(c.value)
+	case "created_at":
+		// archivesFilters has no general-purpose range on created_at, only
+		// the inclusive From/To bounds, so only operators that map onto
+		// those cleanly are accepted; anything else is rejected rather than
+		// silently dropped.
+		switch c.op {
+		case ">=":
+			af.From = unquote(c.value)
+		case "<=":
+			af.To = unquote(c.value)
+		case "=":
+			v := unquote(c.value)
+			af.From, af.To = v, v
+		case "between":
+			lo, hi, err := parseBetween(c.value)
+			if err != nil {
+				return fmt.Errorf("field %q: %w", c.field, err)
+			}
+			af.From, af.To = unquote(lo), unquote(hi)
+		default:
+			return fmt.Errorf("field %q: operator %q is not valid for created_at; archives only supports >=, <=, = and between", c.field, c.op)
+		}
+	case "tags":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		af.Tags = NewPropertyFilterType(includes, toInterfaceSlice(parseValueList(c.value)), false)
+	case "sales":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		af.Sales = NewPropertyFilterType(includes, toInterfaceSlice(parseValueList(c.value)), false)
+	case "goals":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		af.Goals = NewPropertyFilterType(includes, toInterfaceSlice(parseValueList(c.value)), false)
+	default:
+		return fmt.Errorf("unknown archives field %q", c.field)
+	}
+	return nil
+}
+
+func applyChatsClause(cf *chatsFilters, c filterClause) error {
+	switch c.field {
+	case "groups":
+		ids, err := parseUintList(parseValueList(c.value))
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.GroupIDs = ids
+	default:
+		return fmt.Errorf("unknown chats field %q", c.field)
+	}
+	return nil
+}
+
+func applyCustomersClause(cf *customersFilters, c filterClause) error {
+	switch c.field {
+	case "country":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.Country = NewStringFilter(parseValueList(c.value), includes)
+	case "email":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.Email = NewStringFilter(parseValueList(c.value), includes)
+	case "name":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.Name = NewStringFilter(parseValueList(c.value), includes)
+	case "customer_id":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.CustomerID = NewStringFilter(parseValueList(c.value), includes)
+	case "chat_group_ids":
+		includes, err := inclusiveOp(c.op)
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		ints, err := parseInt64List(parseValueList(c.value))
+		if err != nil {
+			return fmt.Errorf("field %q: %w", c.field, err)
+		}
+		cf.ChatGroupIDs = NewIntegerFilter(ints, includes)
+	case "chats_count":
+		if cf.ChatsCount == nil {
+			cf.ChatsCount = &RangeFilter{}
+		}
+		if err := applyRangeOp(c.field, c.op, c.value, func(u func(*RangeFilter)) { u(cf.ChatsCount) }); err != nil {
+			return err
+		}
+	case "threads_count":
+		if cf.ThreadsCount == nil {
+			cf.ThreadsCount = &RangeFilter{}
+		}
+		if err := applyRangeOp(c.field, c.op, c.value, func(u func(*RangeFilter)) { u(cf.ThreadsCount) }); err != nil {
+			return err
+		}
+	case "visits_count":
+		if cf.VisitsCount == nil {
+			cf.VisitsCount = &RangeFilter{}
+		}
+		if err := applyRangeOp(c.field, c.op, c.value, func(u func(*RangeFilter)) { u(cf.VisitsCount) }); err != nil {
+			return err
+		}
+	case "created_at":
+		if cf.CreatedAt == nil {
+			cf.CreatedAt = &DateRangeFilter{}
+		}
+		if err := applyDateRangeOp(c.field, c.op, c.value, func(u func(*DateRangeFilter)) { u(cf.CreatedAt) }); err != nil {
+			return err
+		}
+	case "agent_last_event_created_at":
+		if cf.AgentLastEventCreatedAt == nil {
+			cf.AgentLastEventCreatedAt = &DateRangeFilter{}
+		}
+		if err := applyDateRangeOp(c.field, c.op, c.value, func(u func(*DateRangeFilter)) { u(cf.AgentLastEventCreatedAt) }); err != nil {
+			return err
+		}
+	case "customer_last_event_created_at":
+		if cf.CustomerLastEventCreatedAt == nil {
+			cf.CustomerLastEventCreatedAt = &DateRangeFilter{}
+		}
+		if err := applyDateRangeOp(c.field, c.op, c.value, func(u func(*DateRangeFilter)) { u(cf.CustomerLastEventCreatedAt) }); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown customers field %q", c.field)
+	}
+	return nil
+}
+
+func inclusiveOp(op string) (bool, error) {
+	switch op {
+	case "=", "in":
+		return true, nil
+	case "!=", "!in":
+		return false, nil
+	default:
+		return false, fmt.Errorf("operator %q is not valid here, expected one of =, !=, in, !in", op)
+	}
+}
+
+func parseUintList(values []string) ([]uint, error) {
+	out := make([]uint, 0, len(values))
+	for _, v := range values {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not an unsigned integer", v)
+		}
+		out = append(out, uint(n))
+	}
+	return out, nil
+}
+
+func parseInt64List(values []string) ([]int64, error) {
+	out := make([]int64, 0, len(values))
+	for _, v := range values {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q is not an integer", v)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
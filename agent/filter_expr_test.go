@@ -0,0 +1,110 @@
+package agent
+
+import "testing"
+
+func TestParseFilterExprCustomersRangeOperators(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    *RangeFilter
+		wantErr bool
+	}{
+		{"greater than", "chats_count > 3", &RangeFilter{GT: 3}, false},
+		{"greater or equal", "chats_count >= 3", &RangeFilter{GTE: 3}, false},
+		{"less than", "chats_count < 3", &RangeFilter{LT: 3}, false},
+		{"less or equal", "chats_count <= 3", &RangeFilter{LTE: 3}, false},
+		{"equal", "chats_count = 3", &RangeFilter{EQ: 3}, false},
+		{"between", "chats_count between 1..5", &RangeFilter{GTE: 1, LTE: 5}, false},
+		{"degenerate between collapses to EQ", "chats_count between 3..3", &RangeFilter{EQ: 3}, false},
+		{"zero operand rejected for gt", "chats_count > 0", nil, true},
+		{"zero operand rejected for gte", "chats_count >= 0", nil, true},
+		{"zero operand rejected for lt", "chats_count < 0", nil, true},
+		{"zero operand rejected for lte", "chats_count <= 0", nil, true},
+		{"zero operand allowed for eq", "chats_count = 0", &RangeFilter{EQ: 0}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseFilterExpr("customers", tc.expr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFilterExpr(%q) error = %v, wantErr %v", tc.expr, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			cf, ok := got.(*customersFilters)
+			if !ok {
+				t.Fatalf("ParseFilterExpr(%q) returned %T, want *customersFilters", tc.expr, got)
+			}
+			if *cf.ChatsCount != *tc.want {
+				t.Fatalf("ParseFilterExpr(%q) ChatsCount = %+v, want %+v", tc.expr, *cf.ChatsCount, *tc.want)
+			}
+			if err := cf.Validate(); err != nil {
+				t.Fatalf("ParseFilterExpr(%q) produced a filter that fails Validate(): %v", tc.expr, err)
+			}
+		})
+	}
+}
+
+func TestParseFilterExprArchivesCreatedAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{"from only", `created_at >= "2024-01-01T00:00:00Z"`, "2024-01-01T00:00:00Z", "", false},
+		{"to only", `created_at <= "2024-02-01T00:00:00Z"`, "", "2024-02-01T00:00:00Z", false},
+		{"equal sets both", `created_at = "2024-01-01T00:00:00Z"`, "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z", false},
+		{"between", "created_at between 2024-01-01T00:00:00Z..2024-02-01T00:00:00Z", "2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z", false},
+		{"unsupported operator rejected", `created_at != "2024-01-01T00:00:00Z"`, "", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseFilterExpr("archives", tc.expr)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseFilterExpr(%q) error = %v, wantErr %v", tc.expr, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			af := got.(*archivesFilters)
+			if af.From != tc.wantFrom || af.To != tc.wantTo {
+				t.Fatalf("ParseFilterExpr(%q) From/To = %q/%q, want %q/%q", tc.expr, af.From, af.To, tc.wantFrom, tc.wantTo)
+			}
+		})
+	}
+}
+
+func TestFindOpIgnoresOperatorsInsideQuotes(t *testing.T) {
+	got, err := ParseFilterExpr("customers", `name = ">=weird"`)
+	if err != nil {
+		t.Fatalf("ParseFilterExpr: %v", err)
+	}
+	cf := got.(*customersFilters)
+	if len(cf.Name.Values) != 1 || cf.Name.Values[0] != ">=weird" {
+		t.Fatalf("Name.Values = %v, want [\">=weird\"]", cf.Name.Values)
+	}
+}
+
+func TestParseFilterExprPropertiesClause(t *testing.T) {
+	got, err := ParseFilterExpr("chats", "properties.routing.group_id in [1,2]")
+	if err != nil {
+		t.Fatalf("ParseFilterExpr: %v", err)
+	}
+	cf := got.(*chatsFilters)
+	pf, ok := cf.Properties.(PropertiesFilters)
+	if !ok {
+		t.Fatalf("Properties = %T, want PropertiesFilters", cf.Properties)
+	}
+	f := pf["routing"]["group_id"]
+	if f == nil || len(f.Values) != 2 {
+		t.Fatalf("routing.group_id filter = %+v, want 2 values", f)
+	}
+}
+
+func TestParseFilterExprUnknownTarget(t *testing.T) {
+	if _, err := ParseFilterExpr("bogus", "query = x"); err == nil {
+		t.Fatal("expected an error for an unknown target")
+	}
+}
@@ -14,6 +14,11 @@ type propertyFilterType struct {
 // If the first parameter is passed along with nil values then the last parameter will be ignore and the filter will check only existence of property
 // Otherwise will check if property values match/exclude given values based on the first parameter
 // The last parameter modifies the filter behavior so that it matches only those Chats that have or don't have all values in the property the filter relates to
+//
+// Deprecated: use the typed constructors (NewStringPropertyFilter,
+// NewIntPropertyFilter, NewBoolPropertyFilter, NewFloatPropertyFilter) or
+// NewPropertyFilterTypeChecked, which catch value/type mismatches before the
+// request is sent.
 func NewPropertyFilterType(includes bool, vals []interface{}, requireEveryValue bool) *propertyFilterType {
 	pft := &propertyFilterType{}
 	switch {
@@ -36,7 +41,7 @@ type archivesFilters struct {
 	GroupIDs   []uint              `json:"group_ids,omitempty"`
 	From       string              `json:"from,omitempty"`
 	To         string              `json:"to,omitempty"`
-	Properties PropertiesFilters   `json:"properties,omitempty"`
+	Properties PropertyExpr        `json:"properties,omitempty"`
 	Tags       *propertyFilterType `json:"tags,omitempty"`
 	Sales      *propertyFilterType `json:"sales,omitempty"`
 	Goals      *propertyFilterType `json:"goals,omitempty"`
@@ -44,6 +49,7 @@ type archivesFilters struct {
 	ThreadIDs  []string            `json:"thread_ids,omitempty"`
 	Query      string              `json:"query,omitempty"`
 	EventTypes *eventTypesFilter   `json:"event_types,omitempty"`
+	modifiers  *queryModifiers     `json:"-"`
 }
 
 type eventTypesFilter struct {
@@ -103,8 +109,10 @@ func (af *archivesFilters) ToDate(date string) *archivesFilters {
 	return af
 }
 
-// ByProperties extends archives filter with Chat properties to match
-func (af *archivesFilters) ByProperties(propsFilters PropertiesFilters) *archivesFilters {
+// ByProperties extends archives filter with Chat properties to match. Pass a
+// PropertiesFilters map for a flat, implicit-AND set of clauses, or a
+// PropertyExpr built with And/Or/Not/Leaf for boolean composition.
+func (af *archivesFilters) ByProperties(propsFilters PropertyExpr) *archivesFilters {
 	af.Properties = propsFilters
 	return af
 }
@@ -149,6 +157,26 @@ func (af *archivesFilters) ByEventTypes(includes bool, vals []string, requireEve
 	return af
 }
 
+// SortBy extends the archives filter to sort results by field, descending
+// if desc is true.
+func (af *archivesFilters) SortBy(field string, desc bool) *archivesFilters {
+	af.modifiers = af.modifiers.sortBy(field, desc)
+	return af
+}
+
+// Select extends the archives filter to return only the given fields.
+func (af *archivesFilters) Select(fields ...string) *archivesFilters {
+	af.modifiers = af.modifiers.selectFields(fields)
+	return af
+}
+
+func (af *archivesFilters) queryModifiers() *queryModifiers {
+	if af == nil {
+		return nil
+	}
+	return af.modifiers
+}
+
 // Customer filters
 
 type customersFilters struct {
@@ -164,42 +192,33 @@ type customersFilters struct {
 	AgentLastEventCreatedAt      *DateRangeFilter `json:"agent_last_event_created_at,omitempty"`
 	CustomerLastEventCreatedAt   *DateRangeFilter `json:"customer_last_event_created_at,omitempty"`
 	IncludeCustomersWithoutChats *bool            `json:"include_customers_without_chats,omitempty"`
+	modifiers                    *queryModifiers `json:"-"`
 }
 
-type stringFilter struct {
-	Values        []string `json:"values,omitempty"`
-	ExcludeValues []string `json:"exclude_values,omitempty"`
-}
+// stringFilter is the string-valued Values/ExcludeValues filter slot shared
+// by customersFilters fields.
+type stringFilter = TypedFilter[string]
 
 // NewStringFilter creates new filter for string values
 // `inclusive` parameter controls if the filtered values should match or exclude given values
+//
+// Deprecated: use NewStringFilterChecked, which validates the filter before
+// returning it.
 func NewStringFilter(values []string, inclusive bool) *stringFilter {
-	sf := &stringFilter{}
-	switch {
-	case inclusive:
-		sf.Values = values
-	default:
-		sf.ExcludeValues = values
-	}
-	return sf
+	return newTypedFilter(values, inclusive)
 }
 
-type integerFilter struct {
-	Values        []int64 `json:"values,omitempty"`
-	ExcludeValues []int64 `json:"exclude_values,omitempty"`
-}
+// integerFilter is the integer-valued Values/ExcludeValues filter slot
+// shared by customersFilters fields.
+type integerFilter = TypedFilter[int64]
 
 // NewIntegerFilter creates new filter for integer values
 // `inclusive` parameter controls if the filtered values should match or exclude given values
+//
+// Deprecated: use NewIntegerFilterChecked, which validates the filter
+// before returning it.
 func NewIntegerFilter(values []int64, inclusive bool) *integerFilter {
-	intF := &integerFilter{}
-	switch {
-	case inclusive:
-		intF.Values = values
-	default:
-		intF.ExcludeValues = values
-	}
-	return intF
+	return newTypedFilter(values, inclusive)
 }
 
 // RangeFilter represents structure to define a range in which filtered numbers should be matched
@@ -319,12 +338,33 @@ func (cf *customersFilters) WithIncludeCustomersWithoutChats(value bool) *custom
 	return cf
 }
 
+// SortBy extends the customers filter to sort results by field, descending
+// if desc is true.
+func (cf *customersFilters) SortBy(field string, desc bool) *customersFilters {
+	cf.modifiers = cf.modifiers.sortBy(field, desc)
+	return cf
+}
+
+// Select extends the customers filter to return only the given fields.
+func (cf *customersFilters) Select(fields ...string) *customersFilters {
+	cf.modifiers = cf.modifiers.selectFields(fields)
+	return cf
+}
+
+func (cf *customersFilters) queryModifiers() *queryModifiers {
+	if cf == nil {
+		return nil
+	}
+	return cf.modifiers
+}
+
 // Chats Filters
 type chatsFilters struct {
-	IncludeActive              bool              `json:"include_active,omitempty"`
-	IncludeChatsWithoutThreads bool              `json:"include_chats_without_threads,omitempty"`
-	GroupIDs                   []uint            `json:"group_ids,omitempty"`
-	Properties                 PropertiesFilters `json:"properties,omitempty"`
+	IncludeActive              bool            `json:"include_active,omitempty"`
+	IncludeChatsWithoutThreads bool            `json:"include_chats_without_threads,omitempty"`
+	GroupIDs                   []uint          `json:"group_ids,omitempty"`
+	Properties                 PropertyExpr    `json:"properties,omitempty"`
+	modifiers                  *queryModifiers `json:"-"`
 }
 
 // NewChatsFilters creates empty structure to aggregate filters for Chats in ListChats method
@@ -353,16 +393,39 @@ func (cf *chatsFilters) ByGroups(groupIDs []uint) *chatsFilters {
 	return cf
 }
 
-// ByProperties extends chat filters with Chat properties to match
-func (cf *chatsFilters) ByProperties(propsFilters PropertiesFilters) *chatsFilters {
+// ByProperties extends chat filters with Chat properties to match. Pass a
+// PropertiesFilters map for a flat, implicit-AND set of clauses, or a
+// PropertyExpr built with And/Or/Not/Leaf for boolean composition.
+func (cf *chatsFilters) ByProperties(propsFilters PropertyExpr) *chatsFilters {
 	cf.Properties = propsFilters
 	return cf
 }
 
+// SortBy extends the chats filter to sort results by field, descending if
+// desc is true.
+func (cf *chatsFilters) SortBy(field string, desc bool) *chatsFilters {
+	cf.modifiers = cf.modifiers.sortBy(field, desc)
+	return cf
+}
+
+// Select extends the chats filter to return only the given fields.
+func (cf *chatsFilters) Select(fields ...string) *chatsFilters {
+	cf.modifiers = cf.modifiers.selectFields(fields)
+	return cf
+}
+
+func (cf *chatsFilters) queryModifiers() *queryModifiers {
+	if cf == nil {
+		return nil
+	}
+	return cf.modifiers
+}
+
 // Threads Filters
 type threadsFilters struct {
-	From string `json:"from,omitempty"`
-	To   string `json:"to,omitempty"`
+	From      string          `json:"from,omitempty"`
+	To        string          `json:"to,omitempty"`
+	modifiers *queryModifiers `json:"-"`
 }
 
 // NewThreadsFilters creates empty structure to aggregate filters for Threads in ListThreads method
@@ -381,3 +444,23 @@ func (tf *threadsFilters) ToDate(date string) *threadsFilters {
 	tf.To = date
 	return tf
 }
+
+// SortBy extends the threads filter to sort results by field, descending if
+// desc is true.
+func (tf *threadsFilters) SortBy(field string, desc bool) *threadsFilters {
+	tf.modifiers = tf.modifiers.sortBy(field, desc)
+	return tf
+}
+
+// Select extends the threads filter to return only the given fields.
+func (tf *threadsFilters) Select(fields ...string) *threadsFilters {
+	tf.modifiers = tf.modifiers.selectFields(fields)
+	return tf
+}
+
+func (tf *threadsFilters) queryModifiers() *queryModifiers {
+	if tf == nil {
+		return nil
+	}
+	return tf.modifiers
+}
@@ -0,0 +1,273 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// IterOpts configures an auto-pagination iterator returned by IterArchives,
+// IterChats, IterThreads or IterCustomers.
+type IterOpts struct {
+	// PageSize is the number of items requested per page. Zero uses the
+	// API's default page size.
+	PageSize int
+	// Prefetch is how many fetched pages may wait in a buffer ahead of the
+	// page currently being consumed, clamped to 1-4; one more page may be
+	// fetched while that buffer is full. Zero defaults to 1, i.e. the next
+	// page is requested while the current one is still being consumed.
+	Prefetch int
+	// SortOrder is passed through to the underlying List call, e.g. "asc"
+	// or "desc".
+	SortOrder string
+	// RateLimit caps outgoing page requests to this many per second. Zero
+	// means unlimited.
+	RateLimit float64
+}
+
+func (o IterOpts) prefetch() int {
+	switch {
+	case o.Prefetch < 1:
+		return 1
+	case o.Prefetch > 4:
+		return 4
+	default:
+		return o.Prefetch
+	}
+}
+
+// fetchPageFunc fetches one page of raw items given a page ID ("" for the
+// first page) and returns the items together with the next page ID ("" once
+// there are no more pages).
+type fetchPageFunc func(ctx context.Context, pageID string) (items []rawItem, nextPageID string, err error)
+
+// pageIterator is the shared engine behind ArchiveIterator, ChatIterator,
+// ThreadIterator and CustomerIterator. It hides page_id/next_page_id
+// handling and prefetches pages ahead of the caller to hide round-trip
+// latency.
+type pageIterator struct {
+	pages  chan pageResult
+	cancel context.CancelFunc
+	once   sync.Once
+
+	cur    []rawItem
+	curIdx int
+	value  rawItem
+	err    error
+	done   bool
+}
+
+type pageResult struct {
+	items []rawItem
+	err   error
+}
+
+func newPageIterator(ctx context.Context, fetch fetchPageFunc, opts IterOpts) *pageIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &pageIterator{
+		pages:  make(chan pageResult, opts.prefetch()),
+		cancel: cancel,
+	}
+	go it.run(ctx, fetch, newRateLimiter(opts.RateLimit))
+	return it
+}
+
+func (it *pageIterator) run(ctx context.Context, fetch fetchPageFunc, limiter *rateLimiter) {
+	defer close(it.pages)
+	pageID := ""
+	for {
+		if err := limiter.wait(ctx); err != nil {
+			select {
+			case it.pages <- pageResult{err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		items, nextPageID, err := fetch(ctx, pageID)
+		if err != nil {
+			select {
+			case it.pages <- pageResult{err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		select {
+		case it.pages <- pageResult{items: items}:
+		case <-ctx.Done():
+			return
+		}
+		if nextPageID == "" {
+			return
+		}
+		pageID = nextPageID
+	}
+}
+
+// Next advances the iterator and reports whether a value is available. It
+// blocks until a value is ready, the iterator is exhausted, or ctx is done.
+func (it *pageIterator) Next(ctx context.Context) bool {
+	for it.curIdx >= len(it.cur) {
+		if it.done {
+			return false
+		}
+		select {
+		case res, ok := <-it.pages:
+			if !ok {
+				it.done = true
+				return false
+			}
+			if res.err != nil {
+				it.err = res.err
+				it.done = true
+				return false
+			}
+			it.cur = res.items
+			it.curIdx = 0
+		case <-ctx.Done():
+			it.err = ctx.Err()
+			it.done = true
+			return false
+		}
+	}
+	it.value = it.cur[it.curIdx]
+	it.curIdx++
+	return true
+}
+
+// Err returns the error, if any, that stopped iteration.
+func (it *pageIterator) Err() error { return it.err }
+
+// Close stops prefetching further pages. It is safe to call more than once.
+func (it *pageIterator) Close() error {
+	it.once.Do(it.cancel)
+	return nil
+}
+
+// rateLimiter is a minimal limiter used to cap page fetches per second by
+// spacing consecutive fetches at least 1/rate apart, with no bursts; a
+// non-positive rate disables limiting.
+type rateLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	last     time.Time
+}
+
+func newRateLimiter(ratePerSecond float64) *rateLimiter {
+	if ratePerSecond <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / ratePerSecond)}
+}
+
+func (rl *rateLimiter) wait(ctx context.Context) error {
+	if rl.interval == 0 {
+		return nil
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if !rl.last.IsZero() {
+		if remaining := rl.interval - time.Since(rl.last); remaining > 0 {
+			t := time.NewTimer(remaining)
+			defer t.Stop()
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	rl.last = time.Now()
+	return nil
+}
+
+// ArchiveIterator streams archives across pages. Obtain one with IterArchives.
+type ArchiveIterator struct{ *pageIterator }
+
+// Next advances the iterator. See pageIterator.Next.
+func (it *ArchiveIterator) Next(ctx context.Context) bool { return it.pageIterator.Next(ctx) }
+
+// Value returns the archive set by the most recent call to Next.
+func (it *ArchiveIterator) Value() rawItem { return it.pageIterator.value }
+
+// IterArchives returns an iterator over archives matching f, prefetching
+// pages ahead of consumption to hide round-trip latency.
+func (a *API) IterArchives(ctx context.Context, f *archivesFilters, opts IterOpts) *ArchiveIterator {
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return a.ListArchives(ctx, f, pageID, opts)
+	}
+	return &ArchiveIterator{newPageIterator(ctx, fetch, opts)}
+}
+
+// ChatIterator streams chats across pages. Obtain one with IterChats.
+type ChatIterator struct{ *pageIterator }
+
+// Next advances the iterator. See pageIterator.Next.
+func (it *ChatIterator) Next(ctx context.Context) bool { return it.pageIterator.Next(ctx) }
+
+// Value returns the chat set by the most recent call to Next.
+func (it *ChatIterator) Value() rawItem { return it.pageIterator.value }
+
+// IterChats returns an iterator over chats matching f, prefetching pages
+// ahead of consumption to hide round-trip latency.
+func (a *API) IterChats(ctx context.Context, f *chatsFilters, opts IterOpts) *ChatIterator {
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return a.ListChats(ctx, f, pageID, opts)
+	}
+	return &ChatIterator{newPageIterator(ctx, fetch, opts)}
+}
+
+// ThreadIterator streams threads across pages. Obtain one with IterThreads.
+type ThreadIterator struct{ *pageIterator }
+
+// Next advances the iterator. See pageIterator.Next.
+func (it *ThreadIterator) Next(ctx context.Context) bool { return it.pageIterator.Next(ctx) }
+
+// Value returns the thread set by the most recent call to Next.
+func (it *ThreadIterator) Value() rawItem { return it.pageIterator.value }
+
+// IterThreads returns an iterator over threads matching f, prefetching
+// pages ahead of consumption to hide round-trip latency.
+func (a *API) IterThreads(ctx context.Context, f *threadsFilters, opts IterOpts) *ThreadIterator {
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return a.ListThreads(ctx, f, pageID, opts)
+	}
+	return &ThreadIterator{newPageIterator(ctx, fetch, opts)}
+}
+
+// CustomerIterator streams customers across pages. Obtain one with
+// IterCustomers.
+type CustomerIterator struct{ *pageIterator }
+
+// Next advances the iterator. See pageIterator.Next.
+func (it *CustomerIterator) Next(ctx context.Context) bool { return it.pageIterator.Next(ctx) }
+
+// Value returns the customer set by the most recent call to Next.
+func (it *CustomerIterator) Value() rawItem { return it.pageIterator.value }
+
+// IterCustomers returns an iterator over customers matching f, prefetching
+// pages ahead of consumption to hide round-trip latency.
+func (a *API) IterCustomers(ctx context.Context, f *customersFilters, opts IterOpts) *CustomerIterator {
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return a.ListCustomers(ctx, f, pageID, opts)
+	}
+	return &CustomerIterator{newPageIterator(ctx, fetch, opts)}
+}
+
+// iterator is the common surface implemented by ArchiveIterator,
+// ChatIterator, ThreadIterator and CustomerIterator; Collect uses it to stay
+// resource-agnostic.
+type iterator interface {
+	Next(ctx context.Context) bool
+	Value() rawItem
+	Err() error
+}
+
+// Collect drains iter into a slice of up to max items (max <= 0 means no
+// limit), stopping early on ctx cancellation or an iterator error.
+func Collect(ctx context.Context, iter iterator, max int) ([]rawItem, error) {
+	var out []rawItem
+	for (max <= 0 || len(out) < max) && iter.Next(ctx) {
+		out = append(out, iter.Value())
+	}
+	return out, iter.Err()
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPageIteratorPagination(t *testing.T) {
+	pages := [][]rawItem{
+		{rawItem(`"a"`), rawItem(`"b"`)},
+		{rawItem(`"c"`)},
+	}
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		switch pageID {
+		case "":
+			return pages[0], "page2", nil
+		case "page2":
+			return pages[1], "", nil
+		default:
+			t.Fatalf("unexpected pageID %q", pageID)
+			return nil, "", nil
+		}
+	}
+
+	it := newPageIterator(context.Background(), fetch, IterOpts{})
+	defer it.Close()
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, string(it.value))
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+	want := []string{`"a"`, `"b"`, `"c"`}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPageIteratorPropagatesFetchError(t *testing.T) {
+	wantErr := errors.New("boom")
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return nil, "", wantErr
+	}
+
+	it := newPageIterator(context.Background(), fetch, IterOpts{})
+	defer it.Close()
+
+	if it.Next(context.Background()) {
+		t.Fatal("Next() = true, want false on a fetch error")
+	}
+	if !errors.Is(it.Err(), wantErr) {
+		t.Fatalf("Err() = %v, want %v", it.Err(), wantErr)
+	}
+}
+
+// TestPageIteratorCloseUnblocksErrorSend exercises the case where Close is
+// called before the consumer ever reads from a full pages channel: run must
+// select on ctx.Done() around its error send instead of blocking forever.
+func TestPageIteratorCloseUnblocksErrorSend(t *testing.T) {
+	wantErr := errors.New("boom")
+	var calls int32
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, "", wantErr
+	}
+
+	it := newPageIterator(context.Background(), fetch, IterOpts{})
+	// Close immediately, before ever calling Next.
+	if err := it.Close(); err != nil {
+		t.Fatalf("Close() = %v, want nil", err)
+	}
+}
+
+func TestCollectRespectsMax(t *testing.T) {
+	fetch := func(ctx context.Context, pageID string) ([]rawItem, string, error) {
+		return []rawItem{rawItem(`1`), rawItem(`2`), rawItem(`3`)}, "", nil
+	}
+	it := &ArchiveIterator{newPageIterator(context.Background(), fetch, IterOpts{})}
+	defer it.Close()
+
+	got, err := Collect(context.Background(), it, 2)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Collect returned %d items, want 2", len(got))
+	}
+}
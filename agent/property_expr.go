@@ -0,0 +1,130 @@
+package agent
+
+import "encoding/json"
+
+// PropertyExpr is satisfied both by the legacy flat PropertiesFilters map,
+// which is interpreted as an implicit AND of its clauses, and by the boolean
+// composition built with And, Or, Not and Leaf. It is the type of the
+// Properties field on archivesFilters and chatsFilters.
+type PropertyExpr interface {
+	json.Marshaler
+	propertyExpr()
+}
+
+// MarshalJSON validates pf against any types registered with
+// RegisterPropertyType, then marshals the flat filter map as-is, preserving
+// wire compatibility with callers that never needed boolean composition.
+func (pf PropertiesFilters) MarshalJSON() ([]byte, error) {
+	if err := pf.validate(); err != nil {
+		return nil, err
+	}
+	type alias PropertiesFilters
+	return json.Marshal(alias(pf))
+}
+
+func (pf PropertiesFilters) propertyExpr() {}
+
+type andExpr struct {
+	clauses []PropertyExpr
+}
+
+func (e andExpr) propertyExpr() {}
+
+func (e andExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		And []PropertyExpr `json:"$and"`
+	}{And: e.clauses})
+}
+
+type orExpr struct {
+	clauses []PropertyExpr
+}
+
+func (e orExpr) propertyExpr() {}
+
+func (e orExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Or []PropertyExpr `json:"$or"`
+	}{Or: e.clauses})
+}
+
+type notExpr struct {
+	clause PropertyExpr
+}
+
+func (e notExpr) propertyExpr() {}
+
+func (e notExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Not PropertyExpr `json:"$not"`
+	}{Not: e.clause})
+}
+
+type leafExpr struct {
+	namespace string
+	name      string
+	filter    *propertyFilterType
+}
+
+func (e leafExpr) propertyExpr() {}
+
+func (e leafExpr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(PropertiesFilters{e.namespace: {e.name: e.filter}})
+}
+
+// And composes clauses with a boolean AND, serialized as {"$and":[...]}.
+func And(clauses ...PropertyExpr) PropertyExpr {
+	return andExpr{clauses: clauses}
+}
+
+// Or composes clauses with a boolean OR, serialized as {"$or":[...]}.
+func Or(clauses ...PropertyExpr) PropertyExpr {
+	return orExpr{clauses: clauses}
+}
+
+// Not negates clause, serialized as {"$not": ...}.
+func Not(clause PropertyExpr) PropertyExpr {
+	return notExpr{clause: clause}
+}
+
+// Leaf builds a single property clause for namespace.name, equivalent to a
+// one-entry PropertiesFilters map, for use inside And/Or/Not compositions.
+func Leaf(namespace, name string, f *propertyFilterType) PropertyExpr {
+	return leafExpr{namespace: namespace, name: name, filter: f}
+}
+
+// validatePropertyExpr walks a PropertyExpr tree validating every leaf
+// propertyFilterType it contains, including against any type registered
+// with RegisterPropertyType, so a composed And/Or/Not expression gets the
+// same type checking as the flat PropertiesFilters map.
+func validatePropertyExpr(expr PropertyExpr) error {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case PropertiesFilters:
+		return e.validate()
+	case leafExpr:
+		if err := e.filter.validate(); err != nil {
+			return err
+		}
+		if t, ok := lookupPropertyType(e.namespace, e.name); ok {
+			return e.filter.validateType(t)
+		}
+		return nil
+	case andExpr:
+		for _, c := range e.clauses {
+			if err := validatePropertyExpr(c); err != nil {
+				return err
+			}
+		}
+	case orExpr:
+		for _, c := range e.clauses {
+			if err := validatePropertyExpr(c); err != nil {
+				return err
+			}
+		}
+	case notExpr:
+		return validatePropertyExpr(e.clause)
+	}
+	return nil
+}
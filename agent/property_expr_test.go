@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPropertyExprMarshaling(t *testing.T) {
+	leaf := Leaf("routing", "group_id", NewIntPropertyFilter(true, []int64{1, 2}, false))
+
+	tests := []struct {
+		name string
+		expr PropertyExpr
+		want string
+	}{
+		{
+			name: "leaf",
+			expr: leaf,
+			want: `{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}}`,
+		},
+		{
+			name: "and",
+			expr: And(leaf, leaf),
+			want: `{"$and":[{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}},{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}}]}`,
+		},
+		{
+			name: "or",
+			expr: Or(leaf, leaf),
+			want: `{"$or":[{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}},{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}}]}`,
+		},
+		{
+			name: "not",
+			expr: Not(leaf),
+			want: `{"$not":{"routing":{"group_id":{"values":[1,2],"require_every_value":false}}}}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(tc.expr)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("Marshal() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidatePropertyExprChecksRegisteredType(t *testing.T) {
+	RegisterPropertyType("routing", "priority", PropInt)
+	defer delete(propertyTypeRegistry.m, propertyKey("routing", "priority"))
+
+	bad := Leaf("routing", "priority", NewStringPropertyFilter(true, []string{"high"}, false))
+	if err := validatePropertyExpr(bad); err == nil {
+		t.Fatal("expected a type mismatch error for a string value against a PropInt property")
+	}
+
+	good := Leaf("routing", "priority", NewIntPropertyFilter(true, []int64{1}, false))
+	if err := validatePropertyExpr(good); err != nil {
+		t.Fatalf("validatePropertyExpr(good) = %v, want nil", err)
+	}
+
+	composed := And(good, Or(good, Not(good)))
+	if err := validatePropertyExpr(composed); err != nil {
+		t.Fatalf("validatePropertyExpr(composed) = %v, want nil", err)
+	}
+}
+
+func TestPropertiesFiltersMarshalValidates(t *testing.T) {
+	RegisterPropertyType("routing", "priority", PropInt)
+	defer delete(propertyTypeRegistry.m, propertyKey("routing", "priority"))
+
+	pf := PropertiesFilters{"routing": {"priority": NewStringPropertyFilter(true, []string{"high"}, false)}}
+	if _, err := json.Marshal(pf); err == nil {
+		t.Fatal("expected Marshal to surface the registered-type mismatch")
+	}
+}
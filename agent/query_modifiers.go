@@ -0,0 +1,32 @@
+package agent
+
+// queryModifiers holds the sort and field-projection options attached to a
+// filter builder via SortBy and Select. They are not part of the nested
+// filters object on the wire; the API layer lifts them out to sort_by,
+// sort_order and fields at the top level of the request body.
+type queryModifiers struct {
+	SortBy    string   `json:"sort_by,omitempty"`
+	SortOrder string   `json:"sort_order,omitempty"`
+	Fields    []string `json:"fields,omitempty"`
+}
+
+func (qm *queryModifiers) sortBy(field string, desc bool) *queryModifiers {
+	if qm == nil {
+		qm = &queryModifiers{}
+	}
+	qm.SortBy = field
+	if desc {
+		qm.SortOrder = "desc"
+	} else {
+		qm.SortOrder = "asc"
+	}
+	return qm
+}
+
+func (qm *queryModifiers) selectFields(fields []string) *queryModifiers {
+	if qm == nil {
+		qm = &queryModifiers{}
+	}
+	qm.Fields = fields
+	return qm
+}
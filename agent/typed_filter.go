@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"fmt"
+	"sync"
+)
+
+// TypedFilter is the generic Values/ExcludeValues filter slot used by
+// stringFilter ([TypedFilter[string]]) and integerFilter
+// ([TypedFilter[int64]]).
+type TypedFilter[T comparable] struct {
+	Values        []T `json:"values,omitempty"`
+	ExcludeValues []T `json:"exclude_values,omitempty"`
+}
+
+func newTypedFilter[T comparable](values []T, inclusive bool) *TypedFilter[T] {
+	tf := &TypedFilter[T]{}
+	if inclusive {
+		tf.Values = values
+	} else {
+		tf.ExcludeValues = values
+	}
+	return tf
+}
+
+// validate reports whether the filter is internally consistent.
+func (tf *TypedFilter[T]) validate() error {
+	if tf == nil {
+		return nil
+	}
+	if len(tf.Values) > 0 && len(tf.ExcludeValues) > 0 {
+		return fmt.Errorf("agent: filter cannot set both Values and ExcludeValues")
+	}
+	return nil
+}
+
+// PropType identifies the Go value type expected for a Chat/Customer
+// property. Register one with RegisterPropertyType so that building a
+// PropertiesFilters against that property can be validated before the
+// request is sent.
+type PropType int
+
+const (
+	// PropString marks a property as holding string values.
+	PropString PropType = iota
+	// PropInt marks a property as holding integer values.
+	PropInt
+	// PropBool marks a property as holding boolean values.
+	PropBool
+	// PropFloat marks a property as holding floating-point values.
+	PropFloat
+)
+
+func (t PropType) String() string {
+	switch t {
+	case PropString:
+		return "string"
+	case PropInt:
+		return "int"
+	case PropBool:
+		return "bool"
+	case PropFloat:
+		return "float"
+	default:
+		return "unknown"
+	}
+}
+
+var propertyTypeRegistry = newPropertyTypeRegistry()
+
+type propertyTypes struct {
+	mu sync.RWMutex
+	m  map[string]PropType
+}
+
+func newPropertyTypeRegistry() *propertyTypes {
+	return &propertyTypes{m: map[string]PropType{}}
+}
+
+func propertyKey(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// RegisterPropertyType declares that the Chat/Customer property identified
+// by namespace and name holds values of type t, e.g.
+// RegisterPropertyType("routing", "group_id", PropInt). PropertiesFilters
+// validation and marshaling then reject values of any other type for that
+// property.
+func RegisterPropertyType(namespace, name string, t PropType) {
+	propertyTypeRegistry.mu.Lock()
+	defer propertyTypeRegistry.mu.Unlock()
+	propertyTypeRegistry.m[propertyKey(namespace, name)] = t
+}
+
+func lookupPropertyType(namespace, name string) (PropType, bool) {
+	propertyTypeRegistry.mu.RLock()
+	defer propertyTypeRegistry.mu.RUnlock()
+	t, ok := propertyTypeRegistry.m[propertyKey(namespace, name)]
+	return t, ok
+}
+
+// validateType reports whether every value in pft matches the registered
+// property type t.
+func (pft *propertyFilterType) validateType(t PropType) error {
+	if pft == nil {
+		return nil
+	}
+	for _, v := range pft.Values {
+		if !propValueMatches(v, t) {
+			return fmt.Errorf("agent: value %v does not match registered property type %s", v, t)
+		}
+	}
+	for _, v := range pft.ExcludeValues {
+		if !propValueMatches(v, t) {
+			return fmt.Errorf("agent: value %v does not match registered property type %s", v, t)
+		}
+	}
+	return nil
+}
+
+func propValueMatches(v interface{}, t PropType) bool {
+	switch t {
+	case PropString:
+		_, ok := v.(string)
+		return ok
+	case PropInt:
+		switch n := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		default:
+			return false
+		}
+	case PropBool:
+		_, ok := v.(bool)
+		return ok
+	case PropFloat:
+		switch v.(type) {
+		case float32, float64, int, int64:
+			return true
+		default:
+			return false
+		}
+	default:
+		return true
+	}
+}
+
+func newPropertyFilterFromTyped[T comparable](includes bool, vals []T, requireEveryValue bool) *propertyFilterType {
+	if vals == nil {
+		return NewPropertyFilterType(includes, nil, requireEveryValue)
+	}
+	ifaceVals := make([]interface{}, len(vals))
+	for i, v := range vals {
+		ifaceVals[i] = v
+	}
+	return NewPropertyFilterType(includes, ifaceVals, requireEveryValue)
+}
+
+// NewStringPropertyFilter creates a property filter for a string-valued
+// Chat/Customer property. See NewPropertyFilterType for parameter details.
+func NewStringPropertyFilter(includes bool, vals []string, requireEveryValue bool) *propertyFilterType {
+	return newPropertyFilterFromTyped(includes, vals, requireEveryValue)
+}
+
+// NewIntPropertyFilter creates a property filter for an integer-valued
+// Chat/Customer property. See NewPropertyFilterType for parameter details.
+func NewIntPropertyFilter(includes bool, vals []int64, requireEveryValue bool) *propertyFilterType {
+	return newPropertyFilterFromTyped(includes, vals, requireEveryValue)
+}
+
+// NewBoolPropertyFilter creates a property filter for a boolean-valued
+// Chat/Customer property. See NewPropertyFilterType for parameter details.
+func NewBoolPropertyFilter(includes bool, vals []bool, requireEveryValue bool) *propertyFilterType {
+	return newPropertyFilterFromTyped(includes, vals, requireEveryValue)
+}
+
+// NewFloatPropertyFilter creates a property filter for a floating-point
+// Chat/Customer property. See NewPropertyFilterType for parameter details.
+func NewFloatPropertyFilter(includes bool, vals []float64, requireEveryValue bool) *propertyFilterType {
+	return newPropertyFilterFromTyped(includes, vals, requireEveryValue)
+}
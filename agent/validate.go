@@ -0,0 +1,292 @@
+package agent
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateRangeLayout is the ISO 8601 layout (with microsecond resolution) that the
+// Agent API expects for DateRangeFilter bounds, e.g. 2017-10-12T15:19:21.010200+01:00
+// or 2017-10-12T14:19:21.010200Z in UTC.
+const dateRangeLayout = "2006-01-02T15:04:05.999999Z07:00"
+
+// validate reports whether the property filter is internally consistent.
+func (pft *propertyFilterType) validate() error {
+	if pft == nil {
+		return nil
+	}
+	if pft.Values != nil && pft.ExcludeValues != nil {
+		return fmt.Errorf("agent: property filter cannot set both Values and ExcludeValues")
+	}
+	if pft.RequireEveryValue != nil && *pft.RequireEveryValue {
+		if len(pft.Values) == 0 && len(pft.ExcludeValues) == 0 {
+			return fmt.Errorf("agent: property filter has RequireEveryValue set but no Values or ExcludeValues")
+		}
+	}
+	return nil
+}
+
+// validate reports whether the event types filter is internally consistent.
+func (etf *eventTypesFilter) validate() error {
+	if etf == nil {
+		return nil
+	}
+	if etf.Values != nil && etf.ExcludeValues != nil {
+		return fmt.Errorf("agent: event_types filter cannot set both Values and ExcludeValues")
+	}
+	if etf.RequireEveryValue != nil && *etf.RequireEveryValue {
+		if len(etf.Values) == 0 && len(etf.ExcludeValues) == 0 {
+			return fmt.Errorf("agent: event_types filter has RequireEveryValue set but no Values or ExcludeValues")
+		}
+	}
+	return nil
+}
+
+// validate checks that every property filter that makes up PropertiesFilters
+// is internally consistent (see propertyFilterType.validate), and that any
+// property with a RegisterPropertyType entry only carries values of the
+// registered type.
+func (pf PropertiesFilters) validate() error {
+	for namespace, props := range pf {
+		for name, f := range props {
+			if err := f.validate(); err != nil {
+				return fmt.Errorf("agent: properties filter %q.%q: %w", namespace, name, err)
+			}
+			if t, ok := lookupPropertyType(namespace, name); ok {
+				if err := f.validateType(t); err != nil {
+					return fmt.Errorf("agent: properties filter %q.%q: %w", namespace, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate reports whether the range is internally consistent, e.g. it rejects
+// a range where both LT and LTE are set, or where the lower bound is not
+// below the upper bound. Equal inclusive bounds (GTE == LTE) describe a valid
+// single-point closed interval and are allowed; equality is only rejected
+// when at least one bound is exclusive (LT or GT).
+func (rf *RangeFilter) Validate() error {
+	if rf == nil {
+		return nil
+	}
+	if rf.LT != 0 && rf.LTE != 0 {
+		return fmt.Errorf("agent: range filter cannot set both LT and LTE")
+	}
+	if rf.GT != 0 && rf.GTE != 0 {
+		return fmt.Errorf("agent: range filter cannot set both GT and GTE")
+	}
+	if rf.EQ != 0 && (rf.LT != 0 || rf.LTE != 0 || rf.GT != 0 || rf.GTE != 0) {
+		return fmt.Errorf("agent: range filter cannot combine EQ with LT/LTE/GT/GTE")
+	}
+	upper, hasUpper, upperExclusive := rf.LTE, rf.LT != 0 || rf.LTE != 0, false
+	if rf.LT != 0 {
+		upper, upperExclusive = rf.LT, true
+	}
+	lower, hasLower, lowerExclusive := rf.GTE, rf.GT != 0 || rf.GTE != 0, false
+	if rf.GT != 0 {
+		lower, lowerExclusive = rf.GT, true
+	}
+	if hasUpper && hasLower {
+		if lower > upper || (lower == upper && (upperExclusive || lowerExclusive)) {
+			return fmt.Errorf("agent: range filter lower bound %d must be less than upper bound %d", lower, upper)
+		}
+	}
+	return nil
+}
+
+// Validate reports whether the date range is internally consistent: every
+// non-empty bound must parse as ISO 8601 with microsecond resolution, LT and
+// LTE cannot both be set, GT and GTE cannot both be set, and the lower bound
+// must not come after the upper bound. Equal inclusive bounds (GTE == LTE)
+// describe a valid single-instant closed interval and are allowed; equality
+// is only rejected when at least one bound is exclusive (LT or GT).
+func (drf *DateRangeFilter) Validate() error {
+	if drf == nil {
+		return nil
+	}
+	if drf.LT != "" && drf.LTE != "" {
+		return fmt.Errorf("agent: date range filter cannot set both LT and LTE")
+	}
+	if drf.GT != "" && drf.GTE != "" {
+		return fmt.Errorf("agent: date range filter cannot set both GT and GTE")
+	}
+	if drf.EQ != "" && (drf.LT != "" || drf.LTE != "" || drf.GT != "" || drf.GTE != "") {
+		return fmt.Errorf("agent: date range filter cannot combine EQ with LT/LTE/GT/GTE")
+	}
+
+	parsed := make(map[string]time.Time, 5)
+	for field, value := range map[string]string{"lt": drf.LT, "lte": drf.LTE, "gt": drf.GT, "gte": drf.GTE, "eq": drf.EQ} {
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(dateRangeLayout, value)
+		if err != nil {
+			return fmt.Errorf("agent: date range filter field %q is not a valid ISO 8601 date: %w", field, err)
+		}
+		parsed[field] = t
+	}
+
+	upper, hasUpper, upperExclusive := parsed["lte"], false, false
+	if t, ok := parsed["lt"]; ok {
+		upper, hasUpper, upperExclusive = t, true, true
+	} else if _, ok := parsed["lte"]; ok {
+		hasUpper = true
+	}
+	lower, hasLower, lowerExclusive := parsed["gte"], false, false
+	if t, ok := parsed["gt"]; ok {
+		lower, hasLower, lowerExclusive = t, true, true
+	} else if _, ok := parsed["gte"]; ok {
+		hasLower = true
+	}
+	if hasUpper && hasLower {
+		if lower.After(upper) || (lower.Equal(upper) && (upperExclusive || lowerExclusive)) {
+			return fmt.Errorf("agent: date range filter lower bound %s must be before upper bound %s", lower.Format(dateRangeLayout), upper.Format(dateRangeLayout))
+		}
+	}
+	return nil
+}
+
+// Validate reports whether the archives filter is internally consistent. It
+// rejects ByThreads combined with any other filter (ByThreads already wipes
+// every other field, so combining it with further builder calls is almost
+// always a mistake) and validates every nested filter.
+func (af *archivesFilters) Validate() error {
+	if af == nil {
+		return nil
+	}
+	if len(af.ThreadIDs) > 0 {
+		other := af.Agents != nil || len(af.GroupIDs) > 0 || af.From != "" || af.To != "" ||
+			af.Properties != nil || af.Tags != nil || af.Sales != nil || af.Goals != nil ||
+			len(af.Surveys) > 0 || af.Query != "" || af.EventTypes != nil
+		if other {
+			return fmt.Errorf("agent: archives filter: ByThreads cannot be combined with other filters")
+		}
+	}
+	if err := af.Agents.validate(); err != nil {
+		return fmt.Errorf("agent: archives filter: agents: %w", err)
+	}
+	if err := af.Tags.validate(); err != nil {
+		return fmt.Errorf("agent: archives filter: tags: %w", err)
+	}
+	if err := af.Sales.validate(); err != nil {
+		return fmt.Errorf("agent: archives filter: sales: %w", err)
+	}
+	if err := af.Goals.validate(); err != nil {
+		return fmt.Errorf("agent: archives filter: goals: %w", err)
+	}
+	if err := af.EventTypes.validate(); err != nil {
+		return fmt.Errorf("agent: archives filter: event_types: %w", err)
+	}
+	if err := validatePropertyExpr(af.Properties); err != nil {
+		return fmt.Errorf("agent: archives filter: %w", err)
+	}
+	return nil
+}
+
+// Validate reports whether the customers filter is internally consistent.
+func (cf *customersFilters) Validate() error {
+	if cf == nil {
+		return nil
+	}
+	if err := cf.Country.validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: country: %w", err)
+	}
+	if err := cf.Email.validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: email: %w", err)
+	}
+	if err := cf.Name.validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: name: %w", err)
+	}
+	if err := cf.CustomerID.validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: customer_id: %w", err)
+	}
+	if err := cf.ChatGroupIDs.validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: chat_group_ids: %w", err)
+	}
+	if err := cf.ChatsCount.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: chats_count: %w", err)
+	}
+	if err := cf.ThreadsCount.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: threads_count: %w", err)
+	}
+	if err := cf.VisitsCount.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: visits_count: %w", err)
+	}
+	if err := cf.CreatedAt.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: created_at: %w", err)
+	}
+	if err := cf.AgentLastEventCreatedAt.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: agent_last_event_created_at: %w", err)
+	}
+	if err := cf.CustomerLastEventCreatedAt.Validate(); err != nil {
+		return fmt.Errorf("agent: customers filter: customer_last_event_created_at: %w", err)
+	}
+	return nil
+}
+
+// Validate reports whether the chats filter is internally consistent.
+func (cf *chatsFilters) Validate() error {
+	if cf == nil {
+		return nil
+	}
+	if err := validatePropertyExpr(cf.Properties); err != nil {
+		return fmt.Errorf("agent: chats filter: %w", err)
+	}
+	return nil
+}
+
+// Validate reports whether the threads filter is internally consistent. An
+// empty string passed to FromDate or ToDate is indistinguishable from the
+// bound never being set, so both fields must either be absent or a
+// non-empty date.
+func (tf *threadsFilters) Validate() error {
+	if tf == nil {
+		return nil
+	}
+	if tf.From == "" && tf.To == "" {
+		return nil
+	}
+	if tf.From != "" {
+		if _, err := time.Parse(dateRangeLayout, tf.From); err != nil {
+			return fmt.Errorf("agent: threads filter: from: %w", err)
+		}
+	}
+	if tf.To != "" {
+		if _, err := time.Parse(dateRangeLayout, tf.To); err != nil {
+			return fmt.Errorf("agent: threads filter: to: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewPropertyFilterTypeChecked behaves like NewPropertyFilterType but
+// validates the resulting filter before returning it.
+func NewPropertyFilterTypeChecked(includes bool, vals []interface{}, requireEveryValue bool) (*propertyFilterType, error) {
+	pft := NewPropertyFilterType(includes, vals, requireEveryValue)
+	if err := pft.validate(); err != nil {
+		return nil, err
+	}
+	return pft, nil
+}
+
+// NewStringFilterChecked behaves like NewStringFilter but validates the
+// resulting filter before returning it.
+func NewStringFilterChecked(values []string, inclusive bool) (*stringFilter, error) {
+	sf := NewStringFilter(values, inclusive)
+	if err := sf.validate(); err != nil {
+		return nil, err
+	}
+	return sf, nil
+}
+
+// NewIntegerFilterChecked behaves like NewIntegerFilter but validates the
+// resulting filter before returning it.
+func NewIntegerFilterChecked(values []int64, inclusive bool) (*integerFilter, error) {
+	intF := NewIntegerFilter(values, inclusive)
+	if err := intF.validate(); err != nil {
+		return nil, err
+	}
+	return intF, nil
+}
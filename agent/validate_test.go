@@ -0,0 +1,60 @@
+package agent
+
+import "testing"
+
+func TestRangeFilterValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rf      *RangeFilter
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"lower below upper, both inclusive", &RangeFilter{GTE: 1, LTE: 5}, false},
+		{"equal inclusive bounds", &RangeFilter{GTE: 5, LTE: 5}, false},
+		{"equal bounds, upper exclusive", &RangeFilter{GTE: 5, LT: 5}, true},
+		{"equal bounds, lower exclusive", &RangeFilter{GT: 5, LTE: 5}, true},
+		{"equal bounds, both exclusive", &RangeFilter{GT: 5, LT: 5}, true},
+		{"lower above upper", &RangeFilter{GTE: 6, LTE: 5}, true},
+		{"both LT and LTE set", &RangeFilter{LT: 1, LTE: 2}, true},
+		{"both GT and GTE set", &RangeFilter{GT: 1, GTE: 2}, true},
+		{"EQ combined with GT", &RangeFilter{EQ: 1, GT: 2}, true},
+		{"EQ alone", &RangeFilter{EQ: 1}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rf.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDateRangeFilterValidate(t *testing.T) {
+	const (
+		t1 = "2024-01-01T00:00:00.000000Z"
+		t2 = "2024-06-01T00:00:00.000000Z"
+	)
+	tests := []struct {
+		name    string
+		drf     *DateRangeFilter
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"lower before upper, both inclusive", &DateRangeFilter{GTE: t1, LTE: t2}, false},
+		{"equal inclusive bounds", &DateRangeFilter{GTE: t1, LTE: t1}, false},
+		{"equal bounds, upper exclusive", &DateRangeFilter{GTE: t1, LT: t1}, true},
+		{"equal bounds, lower exclusive", &DateRangeFilter{GT: t1, LTE: t1}, true},
+		{"lower after upper", &DateRangeFilter{GTE: t2, LTE: t1}, true},
+		{"malformed date", &DateRangeFilter{GTE: "not-a-date"}, true},
+		{"both LT and LTE set", &DateRangeFilter{LT: t1, LTE: t2}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.drf.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}